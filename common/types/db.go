@@ -103,6 +103,20 @@ const (
 	ProverTaskFailureTypeUndefined ProverTaskFailureType = iota
 	// ProverTaskFailureTypeTimeout prover task failure of timeout
 	ProverTaskFailureTypeTimeout
+	// ProverTaskFailureTypeInvalidProof prover task failure because the submitted proof did not verify
+	ProverTaskFailureTypeInvalidProof
+	// ProverTaskFailureTypeProverPanic prover task failure because the prover process panicked
+	ProverTaskFailureTypeProverPanic
+	// ProverTaskFailureTypeProverDisconnected prover task failure because the prover disconnected mid-task
+	ProverTaskFailureTypeProverDisconnected
+	// ProverTaskFailureTypeServerAborted prover task failure because the coordinator aborted the task
+	ProverTaskFailureTypeServerAborted
+	// ProverTaskFailureTypeWitnessGenerationFailed prover task failure because witness generation failed
+	ProverTaskFailureTypeWitnessGenerationFailed
+	// ProverTaskFailureTypeProofVerificationMismatch prover task failure because proof verification produced a mismatch
+	ProverTaskFailureTypeProofVerificationMismatch
+	// ProverTaskFailureTypeResourceExhausted prover task failure because the prover ran out of a resource (memory, disk, ...)
+	ProverTaskFailureTypeResourceExhausted
 )
 
 func (r ProverTaskFailureType) String() string {
@@ -111,11 +125,62 @@ func (r ProverTaskFailureType) String() string {
 		return "prover task failure undefined"
 	case ProverTaskFailureTypeTimeout:
 		return "prover task failure timeout"
+	case ProverTaskFailureTypeInvalidProof:
+		return "prover task failure invalid proof"
+	case ProverTaskFailureTypeProverPanic:
+		return "prover task failure prover panic"
+	case ProverTaskFailureTypeProverDisconnected:
+		return "prover task failure prover disconnected"
+	case ProverTaskFailureTypeServerAborted:
+		return "prover task failure server aborted"
+	case ProverTaskFailureTypeWitnessGenerationFailed:
+		return "prover task failure witness generation failed"
+	case ProverTaskFailureTypeProofVerificationMismatch:
+		return "prover task failure proof verification mismatch"
+	case ProverTaskFailureTypeResourceExhausted:
+		return "prover task failure resource exhausted"
 	default:
 		return "illegal prover task failure type"
 	}
 }
 
+// ProverTaskEventType is the type of a prover task state transition published on the prover task
+// datastream. It extends the ProverProveStatus/ProverTaskFailureType enums with the set of
+// lifecycle events external consumers (monitors, dashboards, slashing tooling) care about.
+type ProverTaskEventType uint8
+
+const (
+	// ProverTaskEventUndefined indicates an unknown or unset event type
+	ProverTaskEventUndefined ProverTaskEventType = iota
+	// ProverTaskEventAssigned is emitted when a task transitions into ProverAssigned
+	ProverTaskEventAssigned
+	// ProverTaskEventProofValid is emitted when a task transitions into ProverProofValid
+	ProverTaskEventProofValid
+	// ProverTaskEventProofInvalid is emitted when a task transitions into ProverProofInvalid
+	ProverTaskEventProofInvalid
+	// ProverTaskEventTimeout is emitted when a task fails with ProverTaskFailureTypeTimeout
+	ProverTaskEventTimeout
+	// ProverTaskEventRewardUpdated is emitted when a task's Reward column is set or changed
+	ProverTaskEventRewardUpdated
+)
+
+func (e ProverTaskEventType) String() string {
+	switch e {
+	case ProverTaskEventAssigned:
+		return "ProverTaskEventAssigned"
+	case ProverTaskEventProofValid:
+		return "ProverTaskEventProofValid"
+	case ProverTaskEventProofInvalid:
+		return "ProverTaskEventProofInvalid"
+	case ProverTaskEventTimeout:
+		return "ProverTaskEventTimeout"
+	case ProverTaskEventRewardUpdated:
+		return "ProverTaskEventRewardUpdated"
+	default:
+		return fmt.Sprintf("Undefined (%d)", uint8(e))
+	}
+}
+
 // ProvingStatus block_batch proving_status (unassigned, assigned, proved, verified, submitted)
 type ProvingStatus int
 
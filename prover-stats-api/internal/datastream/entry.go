@@ -0,0 +1,13 @@
+// Package datastream publishes the append-only, monotonically-indexed stream of prover task state
+// transitions that accumulates in the prover_task_events table, so external monitors, dashboards,
+// and slashing tooling can tail task lifecycle without polling the database.
+package datastream
+
+// Entry is one record of the prover task datastream: a state transition of a single ProverTask row,
+// identified by its StreamID, together with the versioned payload describing what changed.
+type Entry struct {
+	StreamID  uint64
+	EntryType uint8
+	Timestamp int64
+	Payload   []byte
+}
@@ -0,0 +1,166 @@
+package datastream
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// frameType identifies the kind of frame exchanged over a Server connection.
+type frameType uint8
+
+const (
+	frameHello    frameType = 1
+	frameStart    frameType = 2
+	frameEntry    frameType = 3
+	frameBookmark frameType = 4
+	frameEnd      frameType = 5
+)
+
+// frameHeaderSize is the size, in bytes, of the length-prefixed frame header: a one byte frame
+// type followed by a four byte big-endian payload length.
+const frameHeaderSize = 5
+
+// maxFrameSize bounds the payload length a peer may declare in a frame header. HELLO/START
+// payloads are a handful of bytes and even the largest ENTRY payload is one ProverTask row delta,
+// so this is generous headroom without letting an unauthenticated client claim a multi-gigabyte
+// allocation per connection.
+const maxFrameSize = 1 << 20 // 1 MiB
+
+// Server speaks a small length-prefixed frame protocol over TCP so external monitors, dashboards,
+// and slashing tooling can tail the prover task datastream without polling the database:
+//
+//	client -> HELLO
+//	server -> HELLO (ack)
+//	client -> START <fromID>
+//	server -> ENTRY, BOOKMARK (one pair per entry, repeated as new entries arrive)
+//	server -> END (when the stream closes, e.g. on shutdown)
+//
+// The server reads no frames from the client after START.
+type Server struct {
+	stream *Stream
+}
+
+// NewServer creates a datastream Server backed by the given Stream.
+func NewServer(stream *Stream) *Server {
+	return &Server{stream: stream}
+}
+
+// ListenAndServe accepts connections on addr until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("datastream.Server.ListenAndServe error: %w, addr: %s", err, addr)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("datastream.Server.ListenAndServe accept error: %w", err)
+		}
+		go s.handleConn(ctx, conn)
+	}
+}
+
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	if _, _, err := readFrame(conn); err != nil {
+		log.Warn("datastream: failed to read HELLO frame", "err", err)
+		return
+	}
+	if err := writeFrame(conn, frameHello, nil); err != nil {
+		log.Warn("datastream: failed to ack HELLO frame", "err", err)
+		return
+	}
+
+	typ, payload, err := readFrame(conn)
+	if err != nil {
+		log.Warn("datastream: failed to read START frame", "err", err)
+		return
+	}
+	if typ != frameStart || len(payload) != 8 {
+		log.Warn("datastream: expected START frame with 8 byte fromID", "typ", typ)
+		return
+	}
+	fromID := binary.BigEndian.Uint64(payload)
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	entries, err := s.stream.Subscribe(connCtx, fromID)
+	if err != nil {
+		log.Warn("datastream: failed to subscribe", "err", err)
+		return
+	}
+
+	for entry := range entries {
+		if err := writeEntryFrame(conn, entry); err != nil {
+			return
+		}
+		if err := writeBookmarkFrame(conn, entry.StreamID); err != nil {
+			return
+		}
+	}
+	_ = writeFrame(conn, frameEnd, nil)
+}
+
+func writeEntryFrame(w io.Writer, entry Entry) error {
+	payload := make([]byte, 13+len(entry.Payload))
+	binary.BigEndian.PutUint64(payload[0:8], entry.StreamID)
+	payload[8] = entry.EntryType
+	binary.BigEndian.PutUint32(payload[9:13], uint32(len(entry.Payload)))
+	copy(payload[13:], entry.Payload)
+	return writeFrame(w, frameEntry, payload)
+}
+
+func writeBookmarkFrame(w io.Writer, streamID uint64) error {
+	payload := make([]byte, 8)
+	binary.BigEndian.PutUint64(payload, streamID)
+	return writeFrame(w, frameBookmark, payload)
+}
+
+func writeFrame(w io.Writer, typ frameType, payload []byte) error {
+	header := make([]byte, frameHeaderSize)
+	header[0] = byte(typ)
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("datastream: write frame header error: %w", err)
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	if _, err := w.Write(payload); err != nil {
+		return fmt.Errorf("datastream: write frame payload error: %w", err)
+	}
+	return nil
+}
+
+func readFrame(r io.Reader) (frameType, []byte, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, fmt.Errorf("datastream: read frame header error: %w", err)
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > maxFrameSize {
+		return 0, nil, fmt.Errorf("datastream: frame length %d exceeds maxFrameSize %d", length, maxFrameSize)
+	}
+	payload := make([]byte, length)
+	if length > 0 {
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return 0, nil, fmt.Errorf("datastream: read frame payload error: %w", err)
+		}
+	}
+	return frameType(header[0]), payload, nil
+}
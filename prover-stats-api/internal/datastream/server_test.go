@@ -0,0 +1,62 @@
+package datastream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteReadFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeFrame(&buf, frameStart, []byte("payload")))
+
+	typ, payload, err := readFrame(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, frameStart, typ)
+	assert.Equal(t, []byte("payload"), payload)
+}
+
+func TestWriteFrameEmptyPayload(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeFrame(&buf, frameHello, nil))
+
+	typ, payload, err := readFrame(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, frameHello, typ)
+	assert.Empty(t, payload)
+}
+
+func TestReadFrameRejectsOversizedLength(t *testing.T) {
+	header := make([]byte, frameHeaderSize)
+	header[0] = byte(frameEntry)
+	binary.BigEndian.PutUint32(header[1:], maxFrameSize+1)
+
+	_, _, err := readFrame(bytes.NewReader(header))
+	assert.Error(t, err)
+}
+
+func TestWriteEntryFrameEncodesStreamIDAndEntryType(t *testing.T) {
+	var buf bytes.Buffer
+	entry := Entry{StreamID: 42, EntryType: 3, Timestamp: 100, Payload: []byte("delta")}
+	assert.NoError(t, writeEntryFrame(&buf, entry))
+
+	typ, payload, err := readFrame(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, frameEntry, typ)
+	assert.Equal(t, entry.StreamID, binary.BigEndian.Uint64(payload[0:8]))
+	assert.Equal(t, entry.EntryType, payload[8])
+	assert.Equal(t, uint32(len(entry.Payload)), binary.BigEndian.Uint32(payload[9:13]))
+	assert.Equal(t, entry.Payload, payload[13:])
+}
+
+func TestWriteBookmarkFrameEncodesStreamID(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeBookmarkFrame(&buf, 7))
+
+	typ, payload, err := readFrame(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, frameBookmark, typ)
+	assert.Equal(t, uint64(7), binary.BigEndian.Uint64(payload))
+}
@@ -0,0 +1,72 @@
+package datastream
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"scroll-tech/prover-stats-api/internal/orm"
+)
+
+const (
+	defaultPollInterval  = 500 * time.Millisecond
+	eventBatchSize       = 256
+	subscriberBufferSize = 64
+)
+
+// Stream tails the prover_task_events table and fans new entries out to subscribers. It is the
+// in-process counterpart to Server, which exposes the same entries to external TCP clients.
+type Stream struct {
+	eventOrm     *orm.ProverTaskEvent
+	pollInterval time.Duration
+}
+
+// NewStream creates a Stream backed by the prover_task_events table.
+func NewStream(db *gorm.DB) *Stream {
+	return &Stream{eventOrm: orm.NewProverTaskEvent(db), pollInterval: defaultPollInterval}
+}
+
+// Subscribe returns a channel of entries with StreamID >= fromID, delivered in order as they are
+// written. The channel is closed when ctx is cancelled or the underlying table can no longer be
+// read; callers should treat closure as "resubscribe from the last StreamID they saw".
+func (s *Stream) Subscribe(ctx context.Context, fromID uint64) (<-chan Entry, error) {
+	ch := make(chan Entry, subscriberBufferSize)
+	go s.run(ctx, fromID, ch)
+	return ch, nil
+}
+
+func (s *Stream) run(ctx context.Context, fromID uint64, ch chan<- Entry) {
+	defer close(ch)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	next := fromID
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		events, err := s.eventOrm.GetEventsFrom(ctx, next, eventBatchSize)
+		if err != nil {
+			return
+		}
+		for _, event := range events {
+			entry := Entry{
+				StreamID:  event.StreamID,
+				EntryType: event.EntryType,
+				Timestamp: event.Timestamp,
+				Payload:   event.Payload,
+			}
+			select {
+			case ch <- entry:
+			case <-ctx.Done():
+				return
+			}
+			next = event.StreamID + 1
+		}
+	}
+}
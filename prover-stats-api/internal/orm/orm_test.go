@@ -4,6 +4,7 @@ import (
 	"context"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -124,3 +125,122 @@ func TestProverTaskOrmUint256(t *testing.T) {
 	assert.Equal(t, resultRewardUint256, rewardUint256)
 	assert.Equal(t, resultRewardUint256.String(), "115792089237316195423570985008687907853269984665640564039457584007913129639935")
 }
+
+func TestMarkProverTaskFailed(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "failed-task-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "pubkey-0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+
+	shouldReassign, backoff, banProver, err := proverTaskOrm.MarkProverTaskFailed(context.Background(), proverTask.UUID, types.ProverTaskFailureTypeTimeout, "deadline exceeded")
+	assert.NoError(t, err)
+	assert.True(t, shouldReassign)
+	assert.False(t, banProver)
+	assert.Equal(t, 10*time.Second, backoff)
+
+	var updated ProverTask
+	assert.NoError(t, db.Model(&ProverTask{}).Where("uuid = ?", proverTask.UUID).First(&updated).Error)
+	assert.Equal(t, int16(types.ProverProofInvalid), updated.ProvingStatus)
+	assert.Equal(t, int16(types.ProverTaskFailureTypeTimeout), updated.FailureType)
+	assert.Equal(t, "deadline exceeded", updated.FailureDetail)
+
+	// a second timeout on the SAME task grows the backoff, since attempt is scoped per task rather
+	// than to the prover's all-time timeout count.
+	_, secondBackoff, _, err := proverTaskOrm.MarkProverTaskFailed(context.Background(), proverTask.UUID, types.ProverTaskFailureTypeTimeout, "deadline exceeded again")
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Second, secondBackoff)
+}
+
+func TestMarkProverTaskFailedBansProverForInvalidProof(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "invalid-proof-task-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "pubkey-0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+
+	shouldReassign, _, banProver, err := proverTaskOrm.MarkProverTaskFailed(context.Background(), proverTask.UUID, types.ProverTaskFailureTypeInvalidProof, "proof did not verify")
+	assert.NoError(t, err)
+	assert.True(t, shouldReassign)
+	assert.True(t, banProver)
+}
+
+func TestProverTaskEventsEmittedOnStateTransitions(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	eventOrm := NewProverTaskEvent(db)
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "events-task-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "pubkey-0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		AssignedAt:      utils.NowUTC(),
+	}
+
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+	events, err := eventOrm.GetEventsFrom(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, uint8(types.ProverTaskEventAssigned), events[0].EntryType)
+	assert.Equal(t, proverTask.UUID.String(), events[0].ProverTaskUUID)
+
+	assert.NoError(t, proverTaskOrm.UpdateProverTaskProvingStatus(context.Background(), proverTask.UUID, types.ProverProofValid))
+	events, err = eventOrm.GetEventsFrom(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, uint8(types.ProverTaskEventProofValid), events[1].EntryType)
+
+	assert.NoError(t, proverTaskOrm.UpdateProverTaskReward(context.Background(), proverTask.UUID, decimal.NewFromInt(42)))
+	events, err = eventOrm.GetEventsFrom(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, uint8(types.ProverTaskEventRewardUpdated), events[2].EntryType)
+
+	var rewarded ProverTask
+	assert.NoError(t, db.Model(&ProverTask{}).Where("uuid = ?", proverTask.UUID).First(&rewarded).Error)
+	assert.True(t, decimal.NewFromInt(42).Equal(rewarded.Reward))
+}
+
+func TestProverTaskEventEmittedOnFailure(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	eventOrm := NewProverTaskEvent(db)
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "failure-event-task-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "pubkey-0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+
+	_, _, _, err = proverTaskOrm.MarkProverTaskFailed(context.Background(), proverTask.UUID, types.ProverTaskFailureTypeResourceExhausted, "oom")
+	assert.NoError(t, err)
+
+	events, err := eventOrm.GetEventsFrom(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, uint8(types.ProverTaskEventProofInvalid), events[1].EntryType)
+}
@@ -0,0 +1,299 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/types"
+)
+
+// reputationDecayFactor is the weight given to historical observations when folding in a new
+// terminal result into the exponentially decayed success ratio. A value close to 1 means the
+// ratio moves slowly and is resilient to a single bad proof; a value close to 0 reacts quickly.
+const reputationDecayFactor = 0.9
+
+// latencySampleWindow bounds the number of recent proving latencies kept per (prover, task type)
+// so the rolling median stays cheap to recompute on every write.
+const latencySampleWindow = 32
+
+// ReputationWeights are the coefficients of the prover selection score:
+// score = Alpha*success_ratio - Beta*normalized_latency - Gamma*recent_timeouts
+type ReputationWeights struct {
+	Alpha   float64
+	Beta    float64
+	Gamma   float64
+	Epsilon float64 // probability of picking a uniformly random candidate instead of the best-scored one
+}
+
+// DefaultReputationWeights are sane defaults for SelectProverForTask when the caller has no
+// opinion: weigh success heavily, penalize latency moderately, and strongly avoid provers that are
+// timing out recently, while still giving new provers a 5% chance to be explored.
+var DefaultReputationWeights = ReputationWeights{
+	Alpha:   1.0,
+	Beta:    0.5,
+	Gamma:   1.0,
+	Epsilon: 0.05,
+}
+
+// ProverReputation is the struct for the prover_reputation table. It tracks, per prover public key
+// and task type, the aggregated track record used to steer task assignment away from chronically
+// failing provers.
+type ProverReputation struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID                 int64     `json:"id" gorm:"column:id"`
+	ProverPublicKey    string    `json:"prover_public_key" gorm:"column:prover_public_key;uniqueIndex:idx_prover_reputation_prover_task_type"`
+	TaskType           int16     `json:"task_type" gorm:"column:task_type;uniqueIndex:idx_prover_reputation_prover_task_type"`
+	AssignedCount      int64     `json:"assigned_count" gorm:"column:assigned_count;default:0"`
+	ValidCount         int64     `json:"valid_count" gorm:"column:valid_count;default:0"`
+	InvalidCount       int64     `json:"invalid_count" gorm:"column:invalid_count;default:0"`
+	TimeoutCount       int64     `json:"timeout_count" gorm:"column:timeout_count;default:0"`
+	SuccessRatio       float64   `json:"success_ratio" gorm:"column:success_ratio;default:0"`
+	LatencySamplesMs   []byte    `json:"latency_samples_ms" gorm:"column:latency_samples_ms;type:jsonb;default:'[]'"`
+	MedianLatencyMs    int64     `json:"median_latency_ms" gorm:"column:median_latency_ms;default:0"`
+	RecentTimeoutCount int64     `json:"recent_timeout_count" gorm:"column:recent_timeout_count;default:0"`
+	CreatedAt          time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt          time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// TableName returns the table name for the ProverReputation model.
+func (*ProverReputation) TableName() string {
+	return "prover_reputation"
+}
+
+// NewProverReputation creates a new ProverReputation instance.
+func NewProverReputation(db *gorm.DB) *ProverReputation {
+	return &ProverReputation{db: db}
+}
+
+// ensureReputationRow makes sure a (proverPublicKey, taskType) reputation row exists, via an
+// INSERT ... ON CONFLICT DO NOTHING keyed on the row's unique index. Two concurrent callers racing
+// to create the same row for a prover's first task of a type therefore both succeed instead of one
+// hitting a unique-constraint error or, worse, both creating a duplicate row.
+func ensureReputationRow(ctx context.Context, tx *gorm.DB, proverPublicKey string, taskType int16) error {
+	row := ProverReputation{ProverPublicKey: proverPublicKey, TaskType: taskType, LatencySamplesMs: []byte("[]")}
+	err := tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "prover_public_key"}, {Name: "task_type"}},
+		DoNothing: true,
+	}).Create(&row).Error
+	if err != nil {
+		return fmt.Errorf("ensureReputationRow error: %w, prover: %s, task type: %d", err, proverPublicKey, taskType)
+	}
+	return nil
+}
+
+// reputationOutcome categorizes a terminal result for the shared recordOutcome implementation,
+// independent of both ProverProveStatus and ProverTaskFailureType, so infrastructure-caused
+// failures can be folded in as reputationOutcomeNeutral without touching SuccessRatio or the
+// valid/invalid counters.
+type reputationOutcome int
+
+const (
+	reputationOutcomeValid reputationOutcome = iota
+	reputationOutcomeInvalid
+	reputationOutcomeTimeout
+	reputationOutcomeNeutral
+)
+
+// RecordOutcome folds a terminal ProverProveStatus into that prover's reputation row, creating it
+// on first use. It must be called within the same transaction that mutates the ProverTask row so
+// the counters never drift out of sync with the task they describe.
+func (o *ProverReputation) RecordOutcome(ctx context.Context, tx *gorm.DB, proverPublicKey string, taskType int16, status types.ProverProveStatus, assignedAt time.Time) (ProverReputation, error) {
+	outcome := reputationOutcomeTimeout
+	switch status {
+	case types.ProverProofValid:
+		outcome = reputationOutcomeValid
+	case types.ProverProofInvalid:
+		outcome = reputationOutcomeInvalid
+	}
+	return o.recordOutcome(ctx, tx, proverPublicKey, taskType, outcome, assignedAt)
+}
+
+// RecordFailureOutcome folds a ProverTaskFailureType into that prover's reputation row. Failure
+// types that are the prover's fault (an invalid proof, a mismatching verification, a panic) count
+// against it exactly like RecordOutcome's ProverProofInvalid. Infrastructure-caused failures
+// (resource exhaustion, disconnects, server aborts, witness generation) are not the prover's fault
+// and are recorded as reputationOutcomeNeutral, matching retry.Decide's banProver=false for those
+// same failure types.
+func (o *ProverReputation) RecordFailureOutcome(ctx context.Context, tx *gorm.DB, proverPublicKey string, taskType int16, failureType types.ProverTaskFailureType, assignedAt time.Time) (ProverReputation, error) {
+	return o.recordOutcome(ctx, tx, proverPublicKey, taskType, failureReputationOutcome(failureType), assignedAt)
+}
+
+func failureReputationOutcome(failureType types.ProverTaskFailureType) reputationOutcome {
+	switch failureType {
+	case types.ProverTaskFailureTypeInvalidProof, types.ProverTaskFailureTypeProofVerificationMismatch, types.ProverTaskFailureTypeProverPanic:
+		return reputationOutcomeInvalid
+	case types.ProverTaskFailureTypeTimeout:
+		return reputationOutcomeTimeout
+	default:
+		return reputationOutcomeNeutral
+	}
+}
+
+// recordOutcome is the shared implementation behind RecordOutcome and RecordFailureOutcome. The
+// row is read with a SELECT ... FOR UPDATE so concurrent terminal results for the same
+// prover/task type serialize instead of racing on the read-modify-write of SuccessRatio and the
+// latency samples.
+func (o *ProverReputation) recordOutcome(ctx context.Context, tx *gorm.DB, proverPublicKey string, taskType int16, outcome reputationOutcome, assignedAt time.Time) (ProverReputation, error) {
+	if err := ensureReputationRow(ctx, tx, proverPublicKey, taskType); err != nil {
+		return ProverReputation{}, err
+	}
+
+	var reputation ProverReputation
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Model(&ProverReputation{}).
+		Where("prover_public_key = ? AND task_type = ?", proverPublicKey, taskType).
+		First(&reputation).Error; err != nil {
+		return ProverReputation{}, fmt.Errorf("ProverReputation.recordOutcome lookup error: %w, prover: %s, task type: %d", err, proverPublicKey, taskType)
+	}
+
+	if outcome == reputationOutcomeNeutral {
+		return reputation, nil
+	}
+
+	reputation.SuccessRatio = reputationDecayFactor*reputation.SuccessRatio + (1-reputationDecayFactor)*boolToFloat(outcome == reputationOutcomeValid)
+
+	switch outcome {
+	case reputationOutcomeValid:
+		reputation.ValidCount++
+		reputation.RecentTimeoutCount = 0
+		if latencyMs := time.Since(assignedAt).Milliseconds(); latencyMs > 0 {
+			samples := appendLatencySample(reputation.LatencySamplesMs, latencyMs)
+			reputation.LatencySamplesMs = samples
+			reputation.MedianLatencyMs = medianOfLatencySamples(samples)
+		}
+	case reputationOutcomeInvalid:
+		reputation.InvalidCount++
+	case reputationOutcomeTimeout:
+		reputation.TimeoutCount++
+		reputation.RecentTimeoutCount++
+	}
+
+	updates := map[string]interface{}{
+		"success_ratio":        reputation.SuccessRatio,
+		"valid_count":          reputation.ValidCount,
+		"invalid_count":        reputation.InvalidCount,
+		"timeout_count":        reputation.TimeoutCount,
+		"recent_timeout_count": reputation.RecentTimeoutCount,
+		"latency_samples_ms":   reputation.LatencySamplesMs,
+		"median_latency_ms":    reputation.MedianLatencyMs,
+	}
+	if err := tx.WithContext(ctx).Model(&ProverReputation{}).
+		Where("prover_public_key = ? AND task_type = ?", proverPublicKey, taskType).
+		Updates(updates).Error; err != nil {
+		return ProverReputation{}, fmt.Errorf("ProverReputation.recordOutcome update error: %w, prover: %s, task type: %d", err, proverPublicKey, taskType)
+	}
+	return reputation, nil
+}
+
+// RecordAssignment bumps the assigned counter for a prover/task type pair, creating the row on
+// first use. It should be called in the same transaction as InsertProverTask. The increment itself
+// is a single atomic "assigned_count + 1" UPDATE, so unlike RecordOutcome it needs no row lock to
+// stay correct under concurrent assignments — only row creation needs to be race-free.
+func (o *ProverReputation) RecordAssignment(ctx context.Context, tx *gorm.DB, proverPublicKey string, taskType int16) error {
+	if err := ensureReputationRow(ctx, tx, proverPublicKey, taskType); err != nil {
+		return err
+	}
+	if err := tx.WithContext(ctx).Model(&ProverReputation{}).
+		Where("prover_public_key = ? AND task_type = ?", proverPublicKey, taskType).
+		Update("assigned_count", gorm.Expr("assigned_count + 1")).Error; err != nil {
+		return fmt.Errorf("ProverReputation.RecordAssignment update error: %w, prover: %s, task type: %d", err, proverPublicKey, taskType)
+	}
+	return nil
+}
+
+// SelectProverForTask picks the candidate best suited to receive a task of the given type, using
+// DefaultReputationWeights. With probability Epsilon it instead returns a uniformly random
+// candidate so provers with no track record yet still get a chance to build one.
+func (o *ProverReputation) SelectProverForTask(ctx context.Context, taskType int16, candidates []string) (string, error) {
+	return o.selectProverForTask(ctx, taskType, candidates, DefaultReputationWeights)
+}
+
+func (o *ProverReputation) selectProverForTask(ctx context.Context, taskType int16, candidates []string, weights ReputationWeights) (string, error) {
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("ProverReputation.SelectProverForTask error: no candidates for task type %d", taskType)
+	}
+	if weights.Epsilon > 0 && rand.Float64() < weights.Epsilon {
+		return candidates[rand.Intn(len(candidates))], nil
+	}
+
+	var reputations []ProverReputation
+	if err := o.db.WithContext(ctx).Model(&ProverReputation{}).
+		Where("prover_public_key in (?) AND task_type = ?", candidates, taskType).
+		Find(&reputations).Error; err != nil {
+		return "", fmt.Errorf("ProverReputation.SelectProverForTask error: %w, task type: %d", err, taskType)
+	}
+	byKey := make(map[string]ProverReputation, len(reputations))
+	for _, r := range reputations {
+		byKey[r.ProverPublicKey] = r
+	}
+
+	maxLatencyMs := int64(0)
+	for _, r := range byKey {
+		if r.MedianLatencyMs > maxLatencyMs {
+			maxLatencyMs = r.MedianLatencyMs
+		}
+	}
+
+	bestCandidate := candidates[0]
+	bestScore := -1.0
+	for i, candidate := range candidates {
+		reputation, known := byKey[candidate]
+		var normalizedLatency float64
+		if known && maxLatencyMs > 0 {
+			normalizedLatency = float64(reputation.MedianLatencyMs) / float64(maxLatencyMs)
+		}
+		score := weights.Alpha*reputation.SuccessRatio - weights.Beta*normalizedLatency - weights.Gamma*float64(reputation.RecentTimeoutCount)
+		if i == 0 || score > bestScore {
+			bestScore = score
+			bestCandidate = candidate
+		}
+	}
+	return bestCandidate, nil
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func appendLatencySample(existing []byte, sampleMs int64) []byte {
+	var samples []int64
+	if len(existing) > 0 {
+		// a malformed or empty column is treated as no history rather than an error, since losing a
+		// few latency samples is harmless and the alternative is failing the status transition.
+		_ = json.Unmarshal(existing, &samples)
+	}
+	samples = append(samples, sampleMs)
+	if len(samples) > latencySampleWindow {
+		samples = samples[len(samples)-latencySampleWindow:]
+	}
+	out, err := json.Marshal(samples)
+	if err != nil {
+		return existing
+	}
+	return out
+}
+
+func medianOfLatencySamples(raw []byte) int64 {
+	var samples []int64
+	if err := json.Unmarshal(raw, &samples); err != nil || len(samples) == 0 {
+		return 0
+	}
+	sorted := make([]int64, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
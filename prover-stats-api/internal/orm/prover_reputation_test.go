@@ -0,0 +1,93 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/database/migrate"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+)
+
+func TestProverReputationRecordAssignment(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "reputation-assignment-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "pubkey-0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+
+	var reputation ProverReputation
+	err = db.Model(&ProverReputation{}).
+		Where("prover_public_key = ? AND task_type = ?", proverTask.ProverPublicKey, proverTask.TaskType).
+		First(&reputation).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), reputation.AssignedCount)
+
+	// a second task assigned to the same prover/task type bumps the same row rather than creating
+	// another one.
+	secondTask := proverTask
+	secondTask.UUID = uuid.UUID{}
+	secondTask.TaskID = "reputation-assignment-hash-2"
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &secondTask))
+
+	err = db.Model(&ProverReputation{}).
+		Where("prover_public_key = ? AND task_type = ?", proverTask.ProverPublicKey, proverTask.TaskType).
+		First(&reputation).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), reputation.AssignedCount)
+}
+
+func TestProverReputationRecordOutcomeDecaysSuccessRatio(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "reputation-outcome-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "pubkey-0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+	assert.NoError(t, proverTaskOrm.UpdateProverTaskProvingStatus(context.Background(), proverTask.UUID, types.ProverProofValid))
+
+	var reputation ProverReputation
+	err = db.Model(&ProverReputation{}).
+		Where("prover_public_key = ? AND task_type = ?", proverTask.ProverPublicKey, proverTask.TaskType).
+		First(&reputation).Error
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), reputation.ValidCount)
+	assert.InDelta(t, (1-reputationDecayFactor), reputation.SuccessRatio, 1e-9)
+}
+
+func TestSelectProverForTaskPrefersHigherScore(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	reputationOrm := NewProverReputation(db)
+	good := ProverReputation{ProverPublicKey: "good-prover", TaskType: int16(message.ProofTypeChunk), SuccessRatio: 0.9, LatencySamplesMs: []byte("[]")}
+	bad := ProverReputation{ProverPublicKey: "bad-prover", TaskType: int16(message.ProofTypeChunk), SuccessRatio: 0.1, RecentTimeoutCount: 3, LatencySamplesMs: []byte("[]")}
+	assert.NoError(t, db.Create(&good).Error)
+	assert.NoError(t, db.Create(&bad).Error)
+
+	// Epsilon is forced to 0 so the test is deterministic.
+	selected, err := reputationOrm.selectProverForTask(context.Background(), int16(message.ProofTypeChunk), []string{"bad-prover", "good-prover"}, ReputationWeights{Alpha: 1, Beta: 0.5, Gamma: 1, Epsilon: 0})
+	assert.NoError(t, err)
+	assert.Equal(t, "good-prover", selected)
+}
@@ -0,0 +1,207 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/prover-stats-api/internal/retry"
+)
+
+// ProverTask is the struct for prover_task table
+type ProverTask struct {
+	db *gorm.DB `gorm:"-"`
+
+	UUID            uuid.UUID       `json:"uuid" gorm:"column:uuid;type:uuid;default:gen_random_uuid()"`
+	TaskID          string          `json:"task_id" gorm:"column:task_id"`
+	TaskType        int16           `json:"task_type" gorm:"column:task_type"`
+	ProverPublicKey string          `json:"prover_public_key" gorm:"column:prover_public_key"`
+	ProverName      string          `json:"prover_name" gorm:"column:prover_name"`
+	ProvingStatus   int16           `json:"proving_status" gorm:"column:proving_status;default:0"`
+	FailureType     int16           `json:"failure_type" gorm:"column:failure_type;default:0"`
+	FailureDetail   string          `json:"failure_detail" gorm:"column:failure_detail;default:''"`
+	Reward          decimal.Decimal `json:"reward" gorm:"column:reward;default:0;type:decimal(78)"`
+	AssignedAt      time.Time       `json:"assigned_at" gorm:"column:assigned_at"`
+	CreatedAt       time.Time       `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt       gorm.DeletedAt  `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// TableName returns the table name for the ProverTask model.
+func (*ProverTask) TableName() string {
+	return "prover_task"
+}
+
+// NewProverTask creates a new ProverTask instance
+func NewProverTask(db *gorm.DB) *ProverTask {
+	return &ProverTask{db: db}
+}
+
+// GetProverTasksByHashes retrieves the ProverTask records associated with a specific set of hashes.
+func (o *ProverTask) GetProverTasksByHashes(ctx context.Context, taskType message.ProofType, hashes []string) ([]ProverTask, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("task_type = ?", int16(taskType))
+	db = db.Where("task_id in (?)", hashes)
+
+	var proverTasks []ProverTask
+	if err := db.Find(&proverTasks).Error; err != nil {
+		return nil, fmt.Errorf("ProverTask.GetProverTasksByHashes error: %w, task type: %v, hashes: %v", err, taskType, hashes)
+	}
+	return proverTasks, nil
+}
+
+// InsertProverTask inserts a new prover assignment record, bumps the prover's assignment count,
+// and publishes a ProverTaskEventAssigned entry on the datastream, all in one transaction so the
+// three never drift out of sync.
+func (o *ProverTask) InsertProverTask(ctx context.Context, proverTask *ProverTask) error {
+	return o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&ProverTask{}).Create(proverTask).Error; err != nil {
+			return fmt.Errorf("ProverTask.InsertProverTask error: %w, prover task: %v", err, proverTask)
+		}
+
+		reputationOrm := &ProverReputation{db: o.db}
+		if err := reputationOrm.RecordAssignment(ctx, tx, proverTask.ProverPublicKey, proverTask.TaskType); err != nil {
+			return err
+		}
+
+		return publishProverTaskEvent(ctx, tx, proverTask, types.ProverTaskEventAssigned)
+	})
+}
+
+// UpdateProverTaskProvingStatus transitions a prover task to a terminal proving status and, within the
+// same transaction, folds the outcome into that prover's reputation for the task's type.
+func (o *ProverTask) UpdateProverTaskProvingStatus(ctx context.Context, uuid uuid.UUID, status types.ProverProveStatus) error {
+	return o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var proverTask ProverTask
+		if err := tx.Model(&ProverTask{}).Where("uuid = ?", uuid).First(&proverTask).Error; err != nil {
+			return fmt.Errorf("ProverTask.UpdateProverTaskProvingStatus error: %w, uuid: %v", err, uuid)
+		}
+
+		updates := map[string]interface{}{"proving_status": int16(status)}
+		if err := tx.Model(&ProverTask{}).Where("uuid = ?", uuid).Updates(updates).Error; err != nil {
+			return fmt.Errorf("ProverTask.UpdateProverTaskProvingStatus error: %w, uuid: %v, status: %v", err, uuid, status)
+		}
+
+		proverTask.ProvingStatus = int16(status)
+		if err := publishProverTaskEvent(ctx, tx, &proverTask, proveStatusEventType(status)); err != nil {
+			return err
+		}
+
+		if !isTerminalProveStatus(status) {
+			return nil
+		}
+
+		reputationOrm := &ProverReputation{db: o.db}
+		_, err := reputationOrm.RecordOutcome(ctx, tx, proverTask.ProverPublicKey, proverTask.TaskType, status, proverTask.AssignedAt)
+		return err
+	})
+}
+
+// UpdateProverTaskReward sets the reward owed for a ProverTask and publishes a
+// ProverTaskEventRewardUpdated entry in the same transaction, so reward changes are visible on the
+// datastream exactly like every other state transition.
+func (o *ProverTask) UpdateProverTaskReward(ctx context.Context, uuid uuid.UUID, reward decimal.Decimal) error {
+	return o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var proverTask ProverTask
+		if err := tx.Model(&ProverTask{}).Where("uuid = ?", uuid).First(&proverTask).Error; err != nil {
+			return fmt.Errorf("ProverTask.UpdateProverTaskReward error: %w, uuid: %v", err, uuid)
+		}
+
+		if err := tx.Model(&ProverTask{}).Where("uuid = ?", uuid).Update("reward", reward).Error; err != nil {
+			return fmt.Errorf("ProverTask.UpdateProverTaskReward error: %w, uuid: %v, reward: %v", err, uuid, reward)
+		}
+
+		proverTask.Reward = reward
+		return publishProverTaskEvent(ctx, tx, &proverTask, types.ProverTaskEventRewardUpdated)
+	})
+}
+
+// MarkProverTaskFailed atomically records a failed prover task — the failure type and detail on
+// the ProverTask row, a reputation update, and a datastream event — in one transaction, and
+// returns the retry.Decide decision for that failure type so the coordinator no longer needs to
+// hard-code how each failure should be handled. The attempt passed to retry.Decide is scoped to
+// this specific task (the number of times this task's UUID has previously timed out), not the
+// prover's all-time reputation aggregate, so backoff resets for every new task.
+func (o *ProverTask) MarkProverTaskFailed(ctx context.Context, id uuid.UUID, failureType types.ProverTaskFailureType, detail string) (shouldReassign bool, backoff time.Duration, banProver bool, err error) {
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var proverTask ProverTask
+		if err := tx.Model(&ProverTask{}).Where("uuid = ?", id).First(&proverTask).Error; err != nil {
+			return fmt.Errorf("ProverTask.MarkProverTaskFailed error: %w, uuid: %v", err, id)
+		}
+
+		updates := map[string]interface{}{
+			"proving_status": int16(types.ProverProofInvalid),
+			"failure_type":   int16(failureType),
+			"failure_detail": detail,
+		}
+		if err := tx.Model(&ProverTask{}).Where("uuid = ?", id).Updates(updates).Error; err != nil {
+			return fmt.Errorf("ProverTask.MarkProverTaskFailed error: %w, uuid: %v, failure type: %v", err, id, failureType)
+		}
+		proverTask.ProvingStatus = int16(types.ProverProofInvalid)
+		proverTask.FailureType = int16(failureType)
+		proverTask.FailureDetail = detail
+
+		eventOrm := &ProverTaskEvent{db: o.db}
+		priorTimeouts, err := eventOrm.CountEventsByType(ctx, tx, proverTask.UUID.String(), types.ProverTaskEventTimeout)
+		if err != nil {
+			return err
+		}
+
+		if err := publishProverTaskEvent(ctx, tx, &proverTask, failureEventType(failureType)); err != nil {
+			return err
+		}
+
+		reputationOrm := &ProverReputation{db: o.db}
+		if _, err := reputationOrm.RecordFailureOutcome(ctx, tx, proverTask.ProverPublicKey, proverTask.TaskType, failureType, proverTask.AssignedAt); err != nil {
+			return err
+		}
+
+		shouldReassign, backoff, banProver = retry.Decide(failureType, int(priorTimeouts))
+		return nil
+	})
+	return shouldReassign, backoff, banProver, err
+}
+
+func isTerminalProveStatus(status types.ProverProveStatus) bool {
+	return status == types.ProverProofValid || status == types.ProverProofInvalid
+}
+
+func failureEventType(failureType types.ProverTaskFailureType) types.ProverTaskEventType {
+	if failureType == types.ProverTaskFailureTypeTimeout {
+		return types.ProverTaskEventTimeout
+	}
+	return types.ProverTaskEventProofInvalid
+}
+
+func proveStatusEventType(status types.ProverProveStatus) types.ProverTaskEventType {
+	switch status {
+	case types.ProverAssigned:
+		return types.ProverTaskEventAssigned
+	case types.ProverProofValid:
+		return types.ProverTaskEventProofValid
+	case types.ProverProofInvalid:
+		return types.ProverTaskEventProofInvalid
+	default:
+		return types.ProverTaskEventUndefined
+	}
+}
+
+// publishProverTaskEvent writes one datastream entry for a ProverTask row change within the
+// caller's transaction, so the event and the row mutation that produced it commit exactly-once
+// together.
+func publishProverTaskEvent(ctx context.Context, tx *gorm.DB, proverTask *ProverTask, eventType types.ProverTaskEventType) error {
+	payload, err := newTaskDeltaPayload(proverTask)
+	if err != nil {
+		return err
+	}
+	eventOrm := &ProverTaskEvent{}
+	_, err = eventOrm.InsertEvent(ctx, tx, proverTask.UUID.String(), eventType, payload, time.Now().Unix())
+	return err
+}
@@ -0,0 +1,135 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+)
+
+// taskDeltaPayloadVersion is bumped whenever the shape of taskDeltaPayload changes, so long-lived
+// datastream consumers can detect and reject entries they don't know how to decode.
+const taskDeltaPayloadVersion = 1
+
+// taskDeltaPayload is the versioned row delta carried in a ProverTaskEvent's Payload. It is kept
+// deliberately close to the ProverTask columns that changed, rather than the whole row, so
+// consumers can apply it as a patch.
+type taskDeltaPayload struct {
+	Version         uint8  `json:"version"`
+	UUID            string `json:"uuid"`
+	TaskID          string `json:"task_id"`
+	TaskType        int16  `json:"task_type"`
+	ProverPublicKey string `json:"prover_public_key"`
+	ProvingStatus   int16  `json:"proving_status"`
+	FailureType     int16  `json:"failure_type"`
+	Reward          string `json:"reward"`
+}
+
+func newTaskDeltaPayload(proverTask *ProverTask) ([]byte, error) {
+	delta := taskDeltaPayload{
+		Version:         taskDeltaPayloadVersion,
+		UUID:            proverTask.UUID.String(),
+		TaskID:          proverTask.TaskID,
+		TaskType:        proverTask.TaskType,
+		ProverPublicKey: proverTask.ProverPublicKey,
+		ProvingStatus:   proverTask.ProvingStatus,
+		FailureType:     proverTask.FailureType,
+		Reward:          proverTask.Reward.String(),
+	}
+	payload, err := json.Marshal(delta)
+	if err != nil {
+		return nil, fmt.Errorf("newTaskDeltaPayload error: %w, uuid: %s", err, proverTask.UUID)
+	}
+	return payload, nil
+}
+
+// ProverTaskEvent is the struct for the prover_task_events table. Each row is one append-only,
+// monotonically-indexed entry in the prover task datastream: the primary key doubles as the
+// streamID so consumers can resume a Subscribe from any point by name.
+type ProverTaskEvent struct {
+	db *gorm.DB `gorm:"-"`
+
+	StreamID       uint64    `json:"stream_id" gorm:"column:stream_id;primaryKey;autoIncrement"`
+	EntryType      uint8     `json:"entry_type" gorm:"column:entry_type"`
+	ProverTaskUUID string    `json:"prover_task_uuid" gorm:"column:prover_task_uuid"`
+	Payload        []byte    `json:"payload" gorm:"column:payload;type:bytea"`
+	Bookmark       uint64    `json:"bookmark" gorm:"column:bookmark;default:0"`
+	Timestamp      int64     `json:"timestamp" gorm:"column:timestamp"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+// TableName returns the table name for the ProverTaskEvent model.
+func (*ProverTaskEvent) TableName() string {
+	return "prover_task_events"
+}
+
+// NewProverTaskEvent creates a new ProverTaskEvent instance.
+func NewProverTaskEvent(db *gorm.DB) *ProverTaskEvent {
+	return &ProverTaskEvent{db: db}
+}
+
+// InsertEvent appends one entry to the datastream within the caller's transaction, so the event
+// row commits exactly-once alongside the ProverTask row mutation that produced it. The assigned
+// StreamID is returned so the caller can use it as the entry's bookmark.
+func (o *ProverTaskEvent) InsertEvent(ctx context.Context, tx *gorm.DB, proverTaskUUID string, entryType types.ProverTaskEventType, payload []byte, timestamp int64) (uint64, error) {
+	event := ProverTaskEvent{
+		ProverTaskUUID: proverTaskUUID,
+		EntryType:      uint8(entryType),
+		Payload:        payload,
+		Timestamp:      timestamp,
+	}
+	if err := tx.WithContext(ctx).Model(&ProverTaskEvent{}).Create(&event).Error; err != nil {
+		return 0, fmt.Errorf("ProverTaskEvent.InsertEvent error: %w, prover task uuid: %s, entry type: %v", err, proverTaskUUID, entryType)
+	}
+	if err := tx.WithContext(ctx).Model(&ProverTaskEvent{}).
+		Where("stream_id = ?", event.StreamID).
+		Update("bookmark", event.StreamID).Error; err != nil {
+		return 0, fmt.Errorf("ProverTaskEvent.InsertEvent bookmark error: %w, stream id: %d", err, event.StreamID)
+	}
+	return event.StreamID, nil
+}
+
+// CountEventsByType returns how many events of entryType have already been published for a given
+// ProverTask, so callers can derive a per-task retry attempt count (e.g. the number of times this
+// specific task has previously timed out) without a dedicated counter column.
+func (o *ProverTaskEvent) CountEventsByType(ctx context.Context, tx *gorm.DB, proverTaskUUID string, entryType types.ProverTaskEventType) (int64, error) {
+	var count int64
+	if err := tx.WithContext(ctx).Model(&ProverTaskEvent{}).
+		Where("prover_task_uuid = ? AND entry_type = ?", proverTaskUUID, uint8(entryType)).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("ProverTaskEvent.CountEventsByType error: %w, prover task uuid: %s, entry type: %v", err, proverTaskUUID, entryType)
+	}
+	return count, nil
+}
+
+// GetEventsFrom returns up to limit events with StreamID >= fromID, in stream order, for a
+// consumer resuming a Subscribe call from a previously bookmarked position.
+func (o *ProverTaskEvent) GetEventsFrom(ctx context.Context, fromID uint64, limit int) ([]ProverTaskEvent, error) {
+	var events []ProverTaskEvent
+	db := o.db.WithContext(ctx).Model(&ProverTaskEvent{}).
+		Where("stream_id >= ?", fromID).
+		Order("stream_id asc").
+		Limit(limit)
+	if err := db.Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("ProverTaskEvent.GetEventsFrom error: %w, from id: %d", err, fromID)
+	}
+	return events, nil
+}
+
+// GetLatestBookmark returns the StreamID of the most recently inserted event, or 0 if the stream
+// is empty.
+func (o *ProverTaskEvent) GetLatestBookmark(ctx context.Context) (uint64, error) {
+	var event ProverTaskEvent
+	err := o.db.WithContext(ctx).Model(&ProverTaskEvent{}).Order("stream_id desc").First(&event).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ProverTaskEvent.GetLatestBookmark error: %w", err)
+	}
+	return event.StreamID, nil
+}
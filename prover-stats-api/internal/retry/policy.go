@@ -0,0 +1,55 @@
+// Package retry decides how the coordinator should react to a failed prover task, so that
+// knowledge of which failures are the prover's fault (and should cost it) versus transient
+// infrastructure noise lives in one place instead of being hard-coded into call sites.
+package retry
+
+import (
+	"time"
+
+	"scroll-tech/common/types"
+)
+
+const (
+	// timeoutBaseBackoff is the backoff applied after a prover's first timeout on a task.
+	timeoutBaseBackoff = 10 * time.Second
+	// timeoutMaxBackoff caps the linear backoff so a chronically slow prover doesn't starve a task.
+	timeoutMaxBackoff = 5 * time.Minute
+)
+
+// Decide returns whether the task should be reassigned, how long to wait before reassigning it,
+// and whether the prover that produced this failure should be banned from the task, for a given
+// ProverTaskFailureType. attempt is the number of times this task has previously failed with
+// ProverTaskFailureTypeTimeout, used to grow the backoff linearly; pass 0 on the first timeout.
+func Decide(failureType types.ProverTaskFailureType, attempt int) (shouldReassign bool, backoff time.Duration, banProver bool) {
+	switch failureType {
+	case types.ProverTaskFailureTypeTimeout:
+		return true, linearBackoff(attempt), false
+	case types.ProverTaskFailureTypeInvalidProof:
+		return true, 0, true
+	case types.ProverTaskFailureTypeProofVerificationMismatch:
+		return true, 0, true
+	case types.ProverTaskFailureTypeProverPanic:
+		return true, timeoutBaseBackoff, true
+	case types.ProverTaskFailureTypeProverDisconnected:
+		return true, timeoutBaseBackoff, false
+	case types.ProverTaskFailureTypeResourceExhausted:
+		return true, 0, false
+	case types.ProverTaskFailureTypeWitnessGenerationFailed:
+		return true, 0, false
+	case types.ProverTaskFailureTypeServerAborted:
+		return true, 0, false
+	default:
+		return true, timeoutBaseBackoff, false
+	}
+}
+
+func linearBackoff(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	backoff := time.Duration(attempt+1) * timeoutBaseBackoff
+	if backoff > timeoutMaxBackoff {
+		return timeoutMaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
+)
+
+func TestDecide(t *testing.T) {
+	tests := []struct {
+		name           string
+		failureType    types.ProverTaskFailureType
+		shouldReassign bool
+		banProver      bool
+	}{
+		{"timeout", types.ProverTaskFailureTypeTimeout, true, false},
+		{"invalid proof bans the prover", types.ProverTaskFailureTypeInvalidProof, true, true},
+		{"proof verification mismatch bans the prover", types.ProverTaskFailureTypeProofVerificationMismatch, true, true},
+		{"prover panic bans the prover", types.ProverTaskFailureTypeProverPanic, true, true},
+		{"prover disconnected does not ban", types.ProverTaskFailureTypeProverDisconnected, true, false},
+		{"resource exhausted reassigns without penalty", types.ProverTaskFailureTypeResourceExhausted, true, false},
+		{"witness generation failed reassigns without penalty", types.ProverTaskFailureTypeWitnessGenerationFailed, true, false},
+		{"server aborted reassigns without penalty", types.ProverTaskFailureTypeServerAborted, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shouldReassign, _, banProver := Decide(tt.failureType, 0)
+			assert.Equal(t, tt.shouldReassign, shouldReassign)
+			assert.Equal(t, tt.banProver, banProver)
+		})
+	}
+}
+
+func TestDecideResourceExhaustedHasNoBackoff(t *testing.T) {
+	_, backoff, _ := Decide(types.ProverTaskFailureTypeResourceExhausted, 0)
+	assert.Zero(t, backoff)
+}
+
+func TestDecideTimeoutBackoffGrowsLinearlyAndCaps(t *testing.T) {
+	_, first, _ := Decide(types.ProverTaskFailureTypeTimeout, 0)
+	_, second, _ := Decide(types.ProverTaskFailureTypeTimeout, 1)
+	assert.Equal(t, timeoutBaseBackoff, first)
+	assert.Equal(t, 2*timeoutBaseBackoff, second)
+
+	_, capped, _ := Decide(types.ProverTaskFailureTypeTimeout, 1000)
+	assert.Equal(t, timeoutMaxBackoff, capped)
+}
+
+func TestDecideTimeoutBackoffTreatsNegativeAttemptAsFirst(t *testing.T) {
+	_, backoff, _ := Decide(types.ProverTaskFailureTypeTimeout, -1)
+	assert.Equal(t, timeoutBaseBackoff, backoff)
+}
+
+func TestLinearBackoffNeverExceedsMax(t *testing.T) {
+	assert.LessOrEqual(t, linearBackoff(10000), timeoutMaxBackoff)
+	assert.Greater(t, linearBackoff(10000), time.Duration(0))
+}